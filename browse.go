@@ -0,0 +1,245 @@
+package atreugo
+
+import (
+	"encoding/json"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ListingItem is a single entry of a directory Listing.
+type ListingItem struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	IsDir   bool      `json:"isDir"`
+}
+
+// Listing is the data rendered by StaticFS.BrowseTemplate (or returned as JSON)
+// when StaticFS.Browse is enabled and a request resolves to a directory.
+type Listing struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	CanGoUp  bool          `json:"canGoUp"`
+	Items    []ListingItem `json:"items"`
+	NumDirs  int           `json:"numDirs"`
+	NumFiles int           `json:"numFiles"`
+	Sort     string        `json:"sort"`
+	Order    string        `json:"order"`
+}
+
+const (
+	browseSortName    = "name"
+	browseSortSize    = "size"
+	browseSortModTime = "modtime"
+
+	browseOrderAsc  = "asc"
+	browseOrderDesc = "desc"
+)
+
+var defaultBrowseTemplate = template.Must(template.New("browse").Funcs(template.FuncMap{
+	"humanSize": humanSize,
+}).Parse(defaultBrowseTemplateSrc))
+
+const defaultBrowseTemplateSrc = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<thead><tr><th>Name</th><th>Size</th><th>Modified</th></tr></thead>
+<tbody>
+{{if .CanGoUp}}<tr><td><a href="../">../</a></td><td>-</td><td>-</td></tr>{{end}}
+{{range .Items}}<tr><td><a href="{{.Path}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td>{{if not .IsDir}}{{humanSize .Size}}{{end}}</td><td>{{.ModTime.Format "2006-01-02 15:04:05"}}</td></tr>
+{{end}}
+</tbody>
+</table>
+<p>{{.NumDirs}} directories, {{.NumFiles}} files</p>
+</body>
+</html>
+`
+
+func humanSize(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + "KMGTPE"[exp:exp+1] + "iB"
+}
+
+func browseIgnored(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maybeServeBrowseListing stats reqPath under fs.Root and, if it's a directory
+// without a resolvable index, renders a directory listing instead of calling next.
+//
+// It returns true if it fully handled the request.
+func maybeServeBrowseListing(ctx *RequestCtx, fs *StaticFS, urlPrefix, reqPath string) bool {
+	if !fs.Browse {
+		return false
+	}
+
+	cleanReqPath := path.Clean("/" + reqPath)
+	fsPath := filepath.Join(fs.Root, filepath.FromSlash(cleanReqPath))
+
+	info, err := os.Stat(fsPath)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	if hasIndexFile(fsPath, fs.IndexNames) {
+		return false
+	}
+
+	entries, err := os.ReadDir(fsPath)
+	if err != nil {
+		ctx.Error("failed to read directory", fasthttp.StatusInternalServerError)
+
+		return true
+	}
+
+	listing := buildListing(ctx, entries, fs.BrowseIgnore, urlPrefix, cleanReqPath)
+
+	if wantsJSON(ctx) {
+		body, err := json.Marshal(listing)
+		if err != nil {
+			ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+
+			return true
+		}
+
+		ctx.SetContentType("application/json; charset=utf-8")
+		ctx.SetBody(body)
+
+		return true
+	}
+
+	tpl := fs.BrowseTemplate
+	if tpl == nil {
+		tpl = defaultBrowseTemplate
+	}
+
+	ctx.SetContentType("text/html; charset=utf-8")
+
+	if err := tpl.Execute(ctx, listing); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+	}
+
+	return true
+}
+
+func hasIndexFile(dir string, indexNames []string) bool {
+	for _, name := range indexNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func wantsJSON(ctx *RequestCtx) bool {
+	accept := string(ctx.Request.Header.Peek(fasthttp.HeaderAccept))
+
+	return strings.Contains(accept, "application/json") &&
+		!strings.Contains(accept, "text/html")
+}
+
+func buildListing(ctx *RequestCtx, entries []os.DirEntry, ignore []string, urlPrefix, reqPath string) *Listing {
+	listing := &Listing{
+		Name:    path.Base(reqPath),
+		Path:    reqPath,
+		CanGoUp: reqPath != "/" && reqPath != "",
+		Sort:    browseSortName,
+		Order:   browseOrderAsc,
+	}
+
+	if sortBy := string(ctx.QueryArgs().Peek("sort")); sortBy != "" {
+		listing.Sort = sortBy
+	}
+
+	if order := string(ctx.QueryArgs().Peek("order")); order != "" {
+		listing.Order = order
+	}
+
+	for _, entry := range entries {
+		if browseIgnored(ignore, entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		item := ListingItem{
+			Name:    entry.Name(),
+			Path:    path.Join(urlPrefix, reqPath, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			IsDir:   entry.IsDir(),
+		}
+
+		if item.IsDir {
+			item.Path += "/"
+			listing.NumDirs++
+		} else {
+			listing.NumFiles++
+		}
+
+		listing.Items = append(listing.Items, item)
+	}
+
+	sortListing(listing.Items, listing.Sort, listing.Order)
+
+	return listing
+}
+
+func sortListing(items []ListingItem, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case browseSortSize:
+			return items[i].Size < items[j].Size
+		case browseSortModTime:
+			return items[i].ModTime.Before(items[j].ModTime)
+		default:
+			return items[i].Name < items[j].Name
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		// Directories are always listed before files, regardless of sort order.
+		if items[i].IsDir != items[j].IsDir {
+			return items[i].IsDir
+		}
+
+		if order == browseOrderDesc {
+			return less(j, i)
+		}
+
+		return less(i, j)
+	})
+}
@@ -0,0 +1,85 @@
+package atreugo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newTestRequestCtx() *RequestCtx {
+	return &RequestCtx{RequestCtx: &fasthttp.RequestCtx{}}
+}
+
+// TestBuildListingUsesMountPath guards against the bug where a browse
+// listing under a NewGroupPath group was built with the group-relative url
+// instead of the group's full mount path, making every link resolve one
+// level too shallow.
+func TestBuildListingUsesMountPath(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "report.txt"), []byte("data"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o750); err != nil {
+		t.Fatalf("Mkdir() error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+
+	ctx := newTestRequestCtx()
+
+	const mountPath = "/admin/files"
+
+	listing := buildListing(ctx, entries, nil, mountPath, "/")
+
+	if len(listing.Items) != 2 {
+		t.Fatalf("len(listing.Items) = %d, want 2", len(listing.Items))
+	}
+
+	for _, item := range listing.Items {
+		if !strings.HasPrefix(item.Path, mountPath+"/") {
+			t.Errorf("item %q has Path %q, want it prefixed with %q", item.Name, item.Path, mountPath+"/")
+		}
+	}
+}
+
+func TestMaybeServeBrowseListingJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "index.css"), []byte("body{}"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	fs := &StaticFS{Root: dir, Browse: true}
+
+	ctx := newTestRequestCtx()
+	ctx.Request.Header.Set("Accept", "application/json")
+
+	const mountPath = "/static"
+
+	if handled := maybeServeBrowseListing(ctx, fs, mountPath, "/"); !handled {
+		t.Fatal("maybeServeBrowseListing() = false, want true for a browsable directory")
+	}
+
+	var listing Listing
+	if err := json.Unmarshal(ctx.Response.Body(), &listing); err != nil {
+		t.Fatalf("json.Unmarshal(response body) error: %v", err)
+	}
+
+	if len(listing.Items) != 1 {
+		t.Fatalf("len(listing.Items) = %d, want 1", len(listing.Items))
+	}
+
+	want := mountPath + "/index.css"
+	if listing.Items[0].Path != want {
+		t.Errorf("listing.Items[0].Path = %q, want %q", listing.Items[0].Path, want)
+	}
+}
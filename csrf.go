@@ -0,0 +1,13 @@
+package atreugo
+
+// CSRFTokenUserValueKey is the RequestCtx user value key under which
+// middlewares.CSRF stores the token for the current request.
+const CSRFTokenUserValueKey = "atreugo_csrf_token"
+
+// CSRFToken returns the CSRF token associated to the current request by the
+// middlewares.CSRF middleware, or an empty string if it wasn't set.
+func (ctx *RequestCtx) CSRFToken() string {
+	token, _ := ctx.UserValue(CSRFTokenUserValueKey).(string)
+
+	return token
+}
@@ -0,0 +1,326 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/savsgio/atreugo/v10"
+	"github.com/valyala/fasthttp"
+)
+
+// Entry is a single structured access log record, built after a request
+// finishes and handed to Formatter or the built-in text/json formatters.
+type Entry struct {
+	Time      time.Time              `json:"time"`
+	RemoteIP  string                 `json:"remoteIp"`
+	Method    string                 `json:"method"`
+	Path      string                 `json:"path"`
+	Query     string                 `json:"query,omitempty"`
+	Protocol  string                 `json:"protocol"`
+	Status    int                    `json:"status"`
+	BytesIn   int                    `json:"bytesIn"`
+	BytesOut  int                    `json:"bytesOut"`
+	Latency   time.Duration          `json:"latency"`
+	Referer   string                 `json:"referer,omitempty"`
+	UserAgent string                 `json:"userAgent,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+// Formatter renders an Entry to bytes for writing to the sink.
+type Formatter func(e Entry) []byte
+
+// LeveledLogger is implemented by loggers that can log at different
+// severities. When AccessLogConfig.Writer's underlying logger implements
+// it (it's detected via a type assertion on Writer), entries are routed by
+// status class instead of written as plain bytes.
+type LeveledLogger interface {
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+// AccessLogConfig configures the AccessLog middleware.
+type AccessLogConfig struct {
+	// Format selects a built-in formatter ("text" or "json"). Ignored if
+	// Formatter is set.
+	//
+	// Optional. Default: "text".
+	Format string
+
+	// Formatter overrides Format with a user-provided renderer.
+	Formatter Formatter
+
+	// Writer is the sink entries are written to. If it implements
+	// LeveledLogger, entries are routed by status class (2xx -> Info,
+	// 4xx -> Warn, 5xx -> Error) instead.
+	//
+	// Optional. Default: os.Stderr.
+	Writer io.Writer
+
+	// Async, when true, writes through a buffered channel instead of
+	// blocking the request on I/O. Entries are dropped if the buffer is full.
+	Async bool
+
+	// AsyncBufferSize is the channel buffer size used when Async is true.
+	//
+	// Optional. Default: 256.
+	AsyncBufferSize int
+
+	// TrustedProxies is a list of CIDRs allowed to set X-Forwarded-For/
+	// X-Real-IP. If empty, those headers are never trusted and ctx.RemoteIP
+	// is used as-is.
+	TrustedProxies []string
+
+	// ExtraUserValues pulls additional fields from RequestCtx.UserValue into
+	// Entry.Extra, keyed by the same name.
+	ExtraUserValues []string
+
+	// SampleRate, in [0, 1], is the fraction of requests logged, chosen
+	// deterministically by hashing the request id. 0 disables sampling
+	// (everything is logged).
+	SampleRate float64
+
+	// RequestIDUserValueKey is the RequestCtx.UserValue key a request id
+	// was stored under (e.g. by RequestIDMiddleware), used to key the
+	// SampleRate hash. Set it to match your own request-id middleware if
+	// it doesn't use the default key.
+	//
+	// Optional. Default: "request_id".
+	RequestIDUserValueKey string
+}
+
+var defaultAccessLogWriter io.Writer = os.Stderr
+
+func (cfg *AccessLogConfig) setDefaults() {
+	if cfg.Formatter == nil {
+		if cfg.Format == "json" {
+			cfg.Formatter = jsonFormatter
+		} else {
+			cfg.Formatter = textFormatter
+		}
+	}
+
+	if cfg.Writer == nil {
+		cfg.Writer = defaultAccessLogWriter
+	}
+
+	if cfg.AsyncBufferSize <= 0 {
+		cfg.AsyncBufferSize = 256
+	}
+
+	if cfg.RequestIDUserValueKey == "" {
+		cfg.RequestIDUserValueKey = requestIDUserValueKey
+	}
+}
+
+func textFormatter(e Entry) []byte {
+	line := fmt.Sprintf("%s %s %q %d %dB %s %q\n",
+		e.RemoteIP, e.Method, e.Path+queryOrEmpty(e.Query), e.Status, e.BytesOut, e.Latency, e.UserAgent)
+
+	return []byte(line)
+}
+
+func queryOrEmpty(q string) string {
+	if q == "" {
+		return ""
+	}
+
+	return "?" + q
+}
+
+func jsonFormatter(e Entry) []byte {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+
+	return append(data, '\n')
+}
+
+const (
+	accessLogStartUserValueKey = "__atreugo_accesslog_start"
+	requestIDUserValueKey      = "request_id"
+)
+
+// AccessLogMiddleware is the Before/After pair registered to time and emit
+// structured access log entries, see AccessLog.
+type AccessLogMiddleware struct {
+	cfg     AccessLogConfig
+	nets    []*net.IPNet
+	entries chan Entry
+}
+
+// AccessLog returns a structured access log Middleware pair. Register its
+// Before method first and After method last, so the recorded latency spans
+// the whole chain (including any TimeoutWithCodeHandler wrapping it, which
+// runs the full Before/view/After chain inside a single goroutine - the
+// After hook still only fires once per request either way).
+func AccessLog(cfg AccessLogConfig) *AccessLogMiddleware {
+	cfg.setDefaults()
+
+	m := &AccessLogMiddleware{cfg: cfg}
+
+	for _, cidr := range cfg.TrustedProxies {
+		if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+			m.nets = append(m.nets, ipnet)
+		}
+	}
+
+	if cfg.Async {
+		m.entries = make(chan Entry, cfg.AsyncBufferSize)
+
+		go m.writeLoop()
+	}
+
+	return m
+}
+
+// Before records the request start time.
+func (m *AccessLogMiddleware) Before(ctx *atreugo.RequestCtx) error {
+	ctx.SetUserValue(accessLogStartUserValueKey, time.Now())
+
+	return ctx.Next()
+}
+
+// After builds and emits the access log Entry for the request.
+func (m *AccessLogMiddleware) After(ctx *atreugo.RequestCtx) error {
+	start, ok := ctx.UserValue(accessLogStartUserValueKey).(time.Time)
+	if !ok {
+		return ctx.Next()
+	}
+
+	if m.cfg.SampleRate > 0 && m.cfg.SampleRate < 1 && !m.sampled(ctx, start) {
+		return ctx.Next()
+	}
+
+	entry := m.buildEntry(ctx, start)
+
+	if m.cfg.Async {
+		select {
+		case m.entries <- entry:
+		default:
+		}
+	} else {
+		m.write(entry)
+	}
+
+	return ctx.Next()
+}
+
+// sampled decides whether this request should be logged. It hashes the
+// request id when one is available, falling back to the remote IP plus the
+// request's own start time (instead of ctx.Path(), which would turn the
+// sample into one coin-flip per endpoint rather than per request) so every
+// request is sampled independently either way.
+func (m *AccessLogMiddleware) sampled(ctx *atreugo.RequestCtx, start time.Time) bool {
+	id := m.requestID(ctx)
+	if id == "" {
+		id = m.remoteIP(ctx) + ":" + strconv.FormatInt(start.UnixNano(), 10)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return float64(h.Sum32()%1000)/1000 < m.cfg.SampleRate
+}
+
+func (m *AccessLogMiddleware) buildEntry(ctx *atreugo.RequestCtx, start time.Time) Entry {
+	entry := Entry{
+		Time:      start,
+		RemoteIP:  m.remoteIP(ctx),
+		Method:    string(ctx.Method()),
+		Path:      string(ctx.Path()),
+		Query:     string(ctx.QueryArgs().QueryString()),
+		Protocol:  string(ctx.Request.Header.Protocol()),
+		Status:    ctx.Response.StatusCode(),
+		BytesIn:   len(ctx.Request.Body()),
+		BytesOut:  len(ctx.Response.Body()),
+		Latency:   time.Since(start),
+		Referer:   string(ctx.Request.Header.Referer()),
+		UserAgent: string(ctx.Request.Header.UserAgent()),
+		RequestID: m.requestID(ctx),
+	}
+
+	for _, key := range m.cfg.ExtraUserValues {
+		if v := ctx.UserValue(key); v != nil {
+			if entry.Extra == nil {
+				entry.Extra = make(map[string]interface{}, len(m.cfg.ExtraUserValues))
+			}
+
+			entry.Extra[key] = v
+		}
+	}
+
+	return entry
+}
+
+func (m *AccessLogMiddleware) requestID(ctx *atreugo.RequestCtx) string {
+	id, _ := ctx.UserValue(m.cfg.RequestIDUserValueKey).(string)
+
+	return id
+}
+
+func (m *AccessLogMiddleware) remoteIP(ctx *atreugo.RequestCtx) string {
+	if len(m.nets) == 0 || !m.trusted(ctx.RemoteIP()) {
+		return ctx.RemoteIP().String()
+	}
+
+	if fwd := string(ctx.Request.Header.Peek(fasthttp.HeaderXForwardedFor)); fwd != "" {
+		parts := strings.Split(fwd, ",")
+
+		return strings.TrimSpace(parts[0])
+	}
+
+	if real := string(ctx.Request.Header.Peek("X-Real-IP")); real != "" {
+		return real
+	}
+
+	return ctx.RemoteIP().String()
+}
+
+func (m *AccessLogMiddleware) trusted(ip net.IP) bool {
+	for _, n := range m.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *AccessLogMiddleware) write(e Entry) {
+	if logger, ok := m.cfg.Writer.(LeveledLogger); ok {
+		m.writeLeveled(logger, e)
+
+		return
+	}
+
+	_, _ = m.cfg.Writer.Write(m.cfg.Formatter(e))
+}
+
+func (m *AccessLogMiddleware) writeLeveled(logger LeveledLogger, e Entry) {
+	line := string(m.cfg.Formatter(e))
+
+	switch {
+	case e.Status >= fasthttp.StatusInternalServerError:
+		logger.Error(line)
+	case e.Status >= fasthttp.StatusBadRequest:
+		logger.Warn(line)
+	default:
+		logger.Info(line)
+	}
+}
+
+func (m *AccessLogMiddleware) writeLoop() {
+	for e := range m.entries {
+		m.write(e)
+	}
+}
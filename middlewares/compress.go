@@ -0,0 +1,310 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/savsgio/atreugo/v10"
+	"github.com/valyala/fasthttp"
+)
+
+// CompressConfig configures the Compress middleware.
+type CompressConfig struct {
+	// Level is the compression level passed to the underlying encoder. A
+	// *int (instead of int) so an explicit 0 (gzip/flate.NoCompression -
+	// store only, fastest) can be told apart from leaving it unset, the
+	// same way session.Config.Secure tells an explicit false from unset.
+	//
+	// Optional. Default: gzip.DefaultCompression (and equivalent for other
+	// encodings).
+	Level *int
+
+	// MinLength is the minimum response body size, in bytes, to compress.
+	// Smaller bodies are left untouched, since compressing them rarely pays off.
+	//
+	// Optional. Default: 256.
+	MinLength int
+
+	// Types is the allow-list of content types eligible for compression,
+	// matched against the response Content-Type (ignoring any charset).
+	//
+	// Optional. Default: text/*, application/json, application/javascript,
+	// image/svg+xml.
+	Types []string
+
+	// Encodings is the preference order used to pick an encoding among the
+	// ones the client accepts (and atreugo knows how to produce).
+	//
+	// Optional. Default: br (if built with the "brotli" build tag), gzip, deflate.
+	Encodings []string
+}
+
+const defaultMinLength = 256
+
+var defaultCompressibleTypes = []string{
+	"text/*",
+	"application/json",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+func (cfg *CompressConfig) setDefaults() {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = defaultMinLength
+	}
+
+	if len(cfg.Types) == 0 {
+		cfg.Types = defaultCompressibleTypes
+	}
+
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = availableEncodings
+	}
+}
+
+type encoder interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressEncoders maps an encoding token (e.g. "gzip") to a constructor
+// for its encoder. The "br" entry is only registered when built with the
+// "brotli" build tag, keeping github.com/andybalholm/brotli out of the
+// default dependency set.
+var compressEncoders = map[string]func(w io.Writer, level int) encoder{
+	"gzip": func(w io.Writer, level int) encoder {
+		enc, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			enc, _ = gzip.NewWriterLevel(w, gzip.DefaultCompression)
+		}
+
+		return enc
+	},
+	"deflate": func(w io.Writer, level int) encoder {
+		enc, err := flate.NewWriter(w, level)
+		if err != nil {
+			enc, _ = flate.NewWriter(w, flate.DefaultCompression)
+		}
+
+		return enc
+	},
+}
+
+// availableEncodings is the default preference order, populated with "br"
+// first when the brotli build tag registers it in compressEncoders's init.
+var availableEncodings = []string{"gzip", "deflate"}
+
+// defaultLevels holds the level used for each encoding when
+// CompressConfig.Level is left unset. The brotli build tag adds its own
+// "br" entry alongside registering its encoder.
+var defaultLevels = map[string]int{
+	"gzip":    gzip.DefaultCompression,
+	"deflate": flate.DefaultCompression,
+}
+
+// resolveLevel returns cfg.Level if the caller set one explicitly (including
+// an explicit 0, meaning NoCompression), falling back to encoding's own
+// default otherwise.
+func resolveLevel(cfg *CompressConfig, encoding string) int {
+	if cfg.Level != nil {
+		return *cfg.Level
+	}
+
+	return defaultLevels[encoding]
+}
+
+var encoderPools sync.Map // map[string]*sync.Pool, keyed by "encoding:level"
+
+func acquireEncoder(name string, level int) encoder {
+	key := name + ":" + strconv.Itoa(level)
+
+	poolAny, _ := encoderPools.LoadOrStore(key, &sync.Pool{
+		New: func() interface{} {
+			return compressEncoders[name](io.Discard, level)
+		},
+	})
+
+	pool, _ := poolAny.(*sync.Pool)
+
+	return pool.Get().(encoder) //nolint:forcetypeassert
+}
+
+func releaseEncoder(name string, level int, enc encoder) {
+	key := name + ":" + strconv.Itoa(level)
+
+	if poolAny, ok := encoderPools.Load(key); ok {
+		pool, _ := poolAny.(*sync.Pool)
+		pool.Put(enc)
+	}
+}
+
+// Compress returns a Middleware that transparently compresses response
+// bodies based on the request's Accept-Encoding header.
+//
+// Register it with Router.UseAfter (or atreugo.Middlewares.After), so it runs
+// once the view has fully written the response body.
+func Compress(cfg CompressConfig) atreugo.Middleware {
+	cfg.setDefaults()
+
+	return func(ctx *atreugo.RequestCtx) error {
+		if shouldCompress(ctx, &cfg) {
+			encoding := negotiateEncoding(ctx, cfg.Encodings)
+			if encoding != "" {
+				compressBody(ctx, &cfg, encoding)
+			}
+		}
+
+		return ctx.Next()
+	}
+}
+
+func shouldCompress(ctx *atreugo.RequestCtx, cfg *CompressConfig) bool {
+	switch ctx.Response.StatusCode() {
+	case fasthttp.StatusNoContent, fasthttp.StatusNotModified:
+		return false
+	}
+
+	if len(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)) > 0 {
+		return false
+	}
+
+	if hasNoTransform(ctx) {
+		return false
+	}
+
+	if len(ctx.Response.Body()) < cfg.MinLength {
+		return false
+	}
+
+	return matchesType(string(ctx.Response.Header.ContentType()), cfg.Types)
+}
+
+func hasNoTransform(ctx *atreugo.RequestCtx) bool {
+	cc := string(ctx.Request.Header.Peek(fasthttp.HeaderCacheControl))
+
+	return strings.Contains(cc, "no-transform")
+}
+
+func matchesType(contentType string, types []string) bool {
+	if idx := strings.IndexByte(contentType, ';'); idx != -1 {
+		contentType = contentType[:idx]
+	}
+
+	contentType = strings.TrimSpace(contentType)
+
+	for _, t := range types {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(contentType, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if contentType == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func negotiateEncoding(ctx *atreugo.RequestCtx, preference []string) string {
+	accept := string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptEncoding))
+	if accept == "" {
+		return ""
+	}
+
+	for _, enc := range preference {
+		if _, ok := compressEncoders[enc]; !ok {
+			continue
+		}
+
+		if acceptsEncoding(accept, enc) {
+			return enc
+		}
+	}
+
+	return ""
+}
+
+// acceptsEncoding reports whether acceptEncoding (an Accept-Encoding header
+// value) accepts encoding, honoring an explicit "q=0" as a refusal of that
+// encoding per RFC 7231 section 5.3.4, even when a "*" entry elsewhere would
+// otherwise accept it.
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	accepted, rejected := false, false
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		token := strings.TrimSpace(part)
+
+		q := 1.0
+
+		if idx := strings.IndexByte(token, ';'); idx != -1 {
+			if parsed, ok := parseQParam(token[idx+1:]); ok {
+				q = parsed
+			}
+
+			token = strings.TrimSpace(token[:idx])
+		}
+
+		if token != encoding && token != "*" {
+			continue
+		}
+
+		if q == 0 {
+			if token == encoding {
+				rejected = true
+			}
+		} else {
+			accepted = true
+		}
+	}
+
+	return accepted && !rejected
+}
+
+// parseQParam parses a single "q=<value>" Accept-Encoding parameter.
+func parseQParam(param string) (float64, bool) {
+	name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+	if !ok || strings.TrimSpace(name) != "q" {
+		return 0, false
+	}
+
+	q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return q, true
+}
+
+func compressBody(ctx *atreugo.RequestCtx, cfg *CompressConfig, encoding string) {
+	body := ctx.Response.Body()
+
+	level := resolveLevel(cfg, encoding)
+
+	enc := acquireEncoder(encoding, level)
+	defer releaseEncoder(encoding, level, enc)
+
+	var buf bytes.Buffer
+
+	enc.Reset(&buf)
+
+	if _, err := enc.Write(body); err != nil {
+		return
+	}
+
+	if err := enc.Close(); err != nil {
+		return
+	}
+
+	ctx.Response.SetBody(buf.Bytes())
+	ctx.Response.Header.Set(fasthttp.HeaderContentEncoding, encoding)
+	ctx.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAcceptEncoding)
+}
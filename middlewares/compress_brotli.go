@@ -0,0 +1,19 @@
+//go:build brotli
+
+package middlewares
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+func init() {
+	compressEncoders["br"] = func(w io.Writer, level int) encoder {
+		return brotli.NewWriterLevel(w, level)
+	}
+
+	defaultLevels["br"] = brotli.DefaultCompression
+
+	availableEncodings = append([]string{"br"}, availableEncodings...)
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import "testing"
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		encoding       string
+		want           bool
+	}{
+		{name: "exact match", acceptEncoding: "gzip, deflate", encoding: "gzip", want: true},
+		{name: "wildcard matches anything", acceptEncoding: "*", encoding: "br", want: true},
+		{name: "no match", acceptEncoding: "deflate", encoding: "gzip", want: false},
+		{name: "ignores unrelated q value", acceptEncoding: "gzip;q=0.8", encoding: "gzip", want: true},
+		{
+			name:           "q=0 explicitly refuses the encoding",
+			acceptEncoding: "gzip;q=0, deflate",
+			encoding:       "gzip",
+			want:           false,
+		},
+		{
+			name:           "q=0 for one encoding doesn't affect another",
+			acceptEncoding: "gzip;q=0, deflate",
+			encoding:       "deflate",
+			want:           true,
+		},
+		{
+			name:           "explicit rejection wins over a wildcard accept",
+			acceptEncoding: "gzip;q=0, *",
+			encoding:       "gzip",
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptsEncoding(tt.acceptEncoding, tt.encoding); got != tt.want {
+				t.Errorf("acceptsEncoding(%q, %q) = %v, want %v", tt.acceptEncoding, tt.encoding, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveLevel(t *testing.T) {
+	zero := 0
+	custom := 3
+
+	tests := []struct {
+		name string
+		cfg  CompressConfig
+		enc  string
+		want int
+	}{
+		{name: "unset falls back to the encoding default", cfg: CompressConfig{}, enc: "gzip", want: defaultLevels["gzip"]},
+		{
+			name: "explicit zero means NoCompression, not the default",
+			cfg:  CompressConfig{Level: &zero},
+			enc:  "gzip",
+			want: 0,
+		},
+		{name: "explicit level is used as-is", cfg: CompressConfig{Level: &custom}, enc: "deflate", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveLevel(&tt.cfg, tt.enc); got != tt.want {
+				t.Errorf("resolveLevel(%+v, %q) = %d, want %d", tt.cfg, tt.enc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesType(t *testing.T) {
+	types := []string{"text/*", "application/json"}
+
+	tests := []struct {
+		name        string
+		contentType string
+		want        bool
+	}{
+		{name: "wildcard prefix match", contentType: "text/html; charset=utf-8", want: true},
+		{name: "exact match", contentType: "application/json", want: true},
+		{name: "no match", contentType: "image/png", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesType(tt.contentType, types); got != tt.want {
+				t.Errorf("matchesType(%q, %v) = %v, want %v", tt.contentType, types, got, tt.want)
+			}
+		})
+	}
+}
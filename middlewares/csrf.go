@@ -0,0 +1,209 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/savsgio/atreugo/v10"
+	"github.com/valyala/fasthttp"
+)
+
+// CSRFConfig is the configuration for the CSRF middleware.
+type CSRFConfig struct {
+	// CookieName is the name of the cookie holding the CSRF token.
+	//
+	// Optional. Default: "_csrf".
+	CookieName string
+
+	// HeaderName is the request header checked for the token on unsafe methods.
+	//
+	// Optional. Default: "X-CSRF-Token".
+	HeaderName string
+
+	// FormField is the form field (urlencoded or multipart) checked for the
+	// token on unsafe methods when no header is present.
+	//
+	// Optional. Default: "csrf_token".
+	FormField string
+
+	// TokenLength is the number of random bytes used to generate a token.
+	//
+	// Optional. Default: 32.
+	TokenLength int
+
+	// TrustedOrigins is a list of origins (scheme://host[:port]) allowed on
+	// cross-origin unsafe requests, checked against the Origin/Referer headers.
+	//
+	// Optional.
+	TrustedOrigins []string
+
+	// ErrorView is called when a request fails CSRF validation.
+	//
+	// Optional. Default: aborts with 403 Forbidden.
+	ErrorView atreugo.View
+
+	// Skipper, when it returns true, skips CSRF validation for the request.
+	//
+	// Optional.
+	Skipper func(ctx *atreugo.RequestCtx) bool
+}
+
+const (
+	defaultCSRFCookieName  = "_csrf"
+	defaultCSRFHeaderName  = "X-CSRF-Token"
+	defaultCSRFFormField   = "csrf_token"
+	defaultCSRFTokenLength = 32
+)
+
+var safeCSRFMethods = map[string]bool{
+	fasthttp.MethodGet:     true,
+	fasthttp.MethodHead:    true,
+	fasthttp.MethodOptions: true,
+	"TRACE":                true,
+}
+
+func defaultCSRFErrorView(ctx *atreugo.RequestCtx) error {
+	ctx.Error("Forbidden - invalid CSRF token", fasthttp.StatusForbidden)
+
+	return nil
+}
+
+func (cfg *CSRFConfig) setDefaults() {
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaultCSRFCookieName
+	}
+
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = defaultCSRFHeaderName
+	}
+
+	if cfg.FormField == "" {
+		cfg.FormField = defaultCSRFFormField
+	}
+
+	if cfg.TokenLength <= 0 {
+		cfg.TokenLength = defaultCSRFTokenLength
+	}
+
+	if cfg.ErrorView == nil {
+		cfg.ErrorView = defaultCSRFErrorView
+	}
+}
+
+// CSRF is the CSRF middleware with the default configuration.
+var CSRF = NewCSRF(CSRFConfig{})
+
+// NewCSRF returns a CSRF middleware protecting state-changing requests with
+// a double-submit cookie token.
+func NewCSRF(cfg CSRFConfig) atreugo.Middleware {
+	cfg.setDefaults()
+
+	return func(ctx *atreugo.RequestCtx) error {
+		if cfg.Skipper != nil && cfg.Skipper(ctx) {
+			return ctx.Next()
+		}
+
+		token := string(ctx.Request.Header.Cookie(cfg.CookieName))
+
+		if safeCSRFMethods[string(ctx.Method())] {
+			if token == "" {
+				generated, err := generateCSRFToken(cfg.TokenLength)
+				if err != nil {
+					return err
+				}
+
+				token = generated
+				setCSRFCookie(ctx, cfg.CookieName, token)
+			}
+
+			ctx.SetUserValue(atreugo.CSRFTokenUserValueKey, token)
+
+			return ctx.Next()
+		}
+
+		if token == "" || !validOrigin(ctx, cfg.TrustedOrigins) {
+			return cfg.ErrorView(ctx)
+		}
+
+		submitted := submittedCSRFToken(ctx, cfg)
+		if submitted == "" || subtle.ConstantTimeCompare([]byte(token), []byte(submitted)) != 1 {
+			return cfg.ErrorView(ctx)
+		}
+
+		ctx.SetUserValue(atreugo.CSRFTokenUserValueKey, token)
+
+		return ctx.Next()
+	}
+}
+
+func generateCSRFToken(length int) (string, error) {
+	buf := make([]byte, length)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func setCSRFCookie(ctx *atreugo.RequestCtx, name, token string) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(name)
+	cookie.SetValue(token)
+	cookie.SetPath("/")
+	cookie.SetHTTPOnly(false)
+	cookie.SetSecure(true)
+	cookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+func submittedCSRFToken(ctx *atreugo.RequestCtx, cfg CSRFConfig) string {
+	if header := string(ctx.Request.Header.Peek(cfg.HeaderName)); header != "" {
+		return header
+	}
+
+	if form := string(ctx.FormValue(cfg.FormField)); form != "" {
+		return form
+	}
+
+	if form, err := ctx.MultipartForm(); err == nil && form != nil {
+		if values := form.Value["Csrf-Token"]; len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+func validOrigin(ctx *atreugo.RequestCtx, trusted []string) bool {
+	if len(trusted) == 0 {
+		return true
+	}
+
+	origin := string(ctx.Request.Header.Peek("Origin"))
+	if origin == "" {
+		if referer := string(ctx.Request.Header.Peek("Referer")); referer != "" {
+			if u, err := url.Parse(referer); err == nil {
+				origin = u.Scheme + "://" + u.Host
+			}
+		}
+	}
+
+	if origin == "" {
+		return false
+	}
+
+	for _, t := range trusted {
+		if strings.EqualFold(t, origin) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/savsgio/atreugo/v10"
+	"github.com/valyala/fasthttp"
+)
+
+func newCSRFTestCtx(method string) *atreugo.RequestCtx {
+	ctx := &atreugo.RequestCtx{RequestCtx: &fasthttp.RequestCtx{}}
+	ctx.Request.Header.SetMethod(method)
+
+	return ctx
+}
+
+func TestCSRFSafeMethodIssuesToken(t *testing.T) {
+	mw := NewCSRF(CSRFConfig{})
+
+	ctx := newCSRFTestCtx(fasthttp.MethodGet)
+
+	if err := mw(ctx); err != nil {
+		t.Fatalf("mw(ctx) returned error: %v", err)
+	}
+
+	if ctx.Response.StatusCode() == fasthttp.StatusForbidden {
+		t.Fatalf("safe method was rejected with %d", ctx.Response.StatusCode())
+	}
+
+	cookie := string(ctx.Response.Header.Peek("Set-Cookie"))
+	if cookie == "" {
+		t.Fatal("no CSRF cookie was set for a safe method request")
+	}
+
+	if token := ctx.CSRFToken(); token == "" {
+		t.Fatal("CSRFToken() is empty after a safe method request")
+	}
+}
+
+func TestCSRFUnsafeMethodRejectsMissingToken(t *testing.T) {
+	mw := NewCSRF(CSRFConfig{})
+
+	ctx := newCSRFTestCtx(fasthttp.MethodPost)
+
+	if err := mw(ctx); err != nil {
+		t.Fatalf("mw(ctx) returned error: %v", err)
+	}
+
+	if got := ctx.Response.StatusCode(); got != fasthttp.StatusForbidden {
+		t.Errorf("Response.StatusCode() = %d, want %d", got, fasthttp.StatusForbidden)
+	}
+}
+
+func TestCSRFUnsafeMethodRejectsMismatchedToken(t *testing.T) {
+	mw := NewCSRF(CSRFConfig{})
+
+	ctx := newCSRFTestCtx(fasthttp.MethodPost)
+	ctx.Request.Header.SetCookie(defaultCSRFCookieName, "cookie-token")
+	ctx.Request.Header.Set(defaultCSRFHeaderName, "some-other-token")
+
+	if err := mw(ctx); err != nil {
+		t.Fatalf("mw(ctx) returned error: %v", err)
+	}
+
+	if got := ctx.Response.StatusCode(); got != fasthttp.StatusForbidden {
+		t.Errorf("Response.StatusCode() = %d, want %d", got, fasthttp.StatusForbidden)
+	}
+}
+
+func TestCSRFUnsafeMethodAcceptsMatchingToken(t *testing.T) {
+	mw := NewCSRF(CSRFConfig{})
+
+	ctx := newCSRFTestCtx(fasthttp.MethodPost)
+	ctx.Request.Header.SetCookie(defaultCSRFCookieName, "matching-token")
+	ctx.Request.Header.Set(defaultCSRFHeaderName, "matching-token")
+
+	if err := mw(ctx); err != nil {
+		t.Fatalf("mw(ctx) returned error: %v", err)
+	}
+
+	if got := ctx.Response.StatusCode(); got == fasthttp.StatusForbidden {
+		t.Errorf("matching token request was rejected with %d", got)
+	}
+
+	if token := ctx.CSRFToken(); token != "matching-token" {
+		t.Errorf("CSRFToken() = %q, want %q", token, "matching-token")
+	}
+}
+
+func TestCSRFTrustedOriginsRejectsUnknownOrigin(t *testing.T) {
+	mw := NewCSRF(CSRFConfig{TrustedOrigins: []string{"https://example.com"}})
+
+	ctx := newCSRFTestCtx(fasthttp.MethodPost)
+	ctx.Request.Header.SetCookie(defaultCSRFCookieName, "matching-token")
+	ctx.Request.Header.Set(defaultCSRFHeaderName, "matching-token")
+	ctx.Request.Header.Set("Origin", "https://evil.example")
+
+	if err := mw(ctx); err != nil {
+		t.Fatalf("mw(ctx) returned error: %v", err)
+	}
+
+	if got := ctx.Response.StatusCode(); got != fasthttp.StatusForbidden {
+		t.Errorf("Response.StatusCode() = %d, want %d", got, fasthttp.StatusForbidden)
+	}
+}
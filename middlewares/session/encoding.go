@@ -0,0 +1,66 @@
+package session
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Serializer encodes and decodes session data. The default is gob, swappable
+// with e.g. JSON or msgpack via Config.Serializer.
+type Serializer interface {
+	Encode(values map[string]interface{}) ([]byte, error)
+	Decode(data []byte) (map[string]interface{}, error)
+}
+
+func init() {
+	// Session.Flash stores its queue as a []interface{}, which gob doesn't
+	// register by default.
+	gob.Register([]interface{}(nil))
+}
+
+type gobSerializer struct{}
+
+func (gobSerializer) Encode(values map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobSerializer) Decode(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if len(data) == 0 {
+		return values, nil
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// GobSerializer is the default Serializer, based on encoding/gob.
+var GobSerializer Serializer = gobSerializer{}
+
+func encodeFileEntry(e fileEntry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeFileEntry(data []byte) (fileEntry, error) {
+	var e fileEntry
+
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e)
+
+	return e, err
+}
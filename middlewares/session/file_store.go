@@ -0,0 +1,82 @@
+package session
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileStore is a Store backed by one file per session under Dir.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a FileStore persisting session data under dir, which
+// is created if it doesn't already exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{Dir: dir}, nil
+}
+
+type fileEntry struct {
+	ExpiresAt time.Time
+	Data      []byte
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, hex.EncodeToString([]byte(id)))
+}
+
+// Get implements Store.
+func (s *FileStore) Get(id string) ([]byte, error) {
+	raw, err := os.ReadFile(s.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	entry, err := decodeFileEntry(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		_ = s.Delete(id)
+
+		return nil, ErrNotFound
+	}
+
+	return entry.Data, nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(id string, data []byte, ttl time.Duration) error {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	raw, err := encodeFileEntry(fileEntry{ExpiresAt: expiresAt, Data: data})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(id), raw, 0o600)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
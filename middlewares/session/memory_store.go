@@ -0,0 +1,65 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
+}
+
+// MemoryStore is an in-memory Store, suitable for single-instance
+// deployments, development and tests. Data does not survive a restart.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryStore returns a ready to use MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(id string) ([]byte, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[id]
+	s.mu.RUnlock()
+
+	if !ok || entry.expired() {
+		return nil, ErrNotFound
+	}
+
+	return entry.data, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(id string, data []byte, ttl time.Duration) error {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	s.entries[id] = memoryEntry{data: data, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	delete(s.entries, id)
+	s.mu.Unlock()
+
+	return nil
+}
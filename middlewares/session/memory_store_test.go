@@ -0,0 +1,54 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreRoundTrip(t *testing.T) {
+	store := NewMemoryStore()
+
+	if err := store.Save("id1", []byte("payload"), 0); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	data, err := store.Get("id1")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	if string(data) != "payload" {
+		t.Errorf("Get() = %q, want %q", data, "payload")
+	}
+
+	if err := store.Delete("id1"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+
+	if _, err := store.Get("id1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreGetMissing(t *testing.T) {
+	store := NewMemoryStore()
+
+	if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	store := NewMemoryStore()
+
+	if err := store.Save("id1", []byte("payload"), time.Millisecond); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get("id1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after ttl elapsed error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,304 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/savsgio/atreugo/v10"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrStoreRequired is returned by New when Config.Store is nil.
+var ErrStoreRequired = errors.New("session: Config.Store is required")
+
+// ErrSecretRequired is returned by New when Config.Secret is empty. Without
+// it, session id cookies would be signed with a nil HMAC key, letting anyone
+// forge a valid session.
+var ErrSecretRequired = errors.New("session: Config.Secret is required")
+
+const (
+	defaultCookieName = "atreugo_session"
+	defaultPath       = "/"
+	defaultMaxAge     = 24 * time.Hour
+
+	sessionUserValueKey = "__atreugo_session_mw"
+)
+
+// Config configures the Session middleware.
+type Config struct {
+	// Store persists session data. Required.
+	Store Store
+
+	// CookieName is the name of the session cookie.
+	//
+	// Optional. Default: "atreugo_session".
+	CookieName string
+
+	// Path is the cookie path.
+	//
+	// Optional. Default: "/".
+	Path string
+
+	// Domain is the cookie domain.
+	//
+	// Optional.
+	Domain string
+
+	// SameSite is the cookie SameSite attribute.
+	//
+	// Optional. Default: fasthttp.CookieSameSiteLaxMode. The zero value,
+	// fasthttp.CookieSameSiteDisabled, is treated as unset.
+	SameSite fasthttp.CookieSameSite
+
+	// Secure marks the cookie as Secure. A *bool (instead of bool) so that
+	// an explicit false (e.g. for local HTTP development) can be told apart
+	// from leaving it unset.
+	//
+	// Optional. Default: true.
+	Secure *bool
+
+	// MaxAge is both the cookie lifetime and the Store TTL.
+	//
+	// Optional. Default: 24h.
+	MaxAge time.Duration
+
+	// Secret signs the session id cookie with HMAC-SHA256 so it can't be
+	// forged or tampered with. Required.
+	Secret []byte
+
+	// Serializer (de)serializes session values.
+	//
+	// Optional. Default: GobSerializer.
+	Serializer Serializer
+
+	// SecretBox, when set, encrypts the persisted session payload.
+	SecretBox *SecretBox
+}
+
+func (cfg *Config) setDefaults() {
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaultCookieName
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = defaultPath
+	}
+
+	if cfg.SameSite == fasthttp.CookieSameSiteDisabled {
+		cfg.SameSite = fasthttp.CookieSameSiteLaxMode
+	}
+
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = defaultMaxAge
+	}
+
+	if cfg.Serializer == nil {
+		cfg.Serializer = GobSerializer
+	}
+
+	if cfg.Secure == nil {
+		secure := true
+		cfg.Secure = &secure
+	}
+}
+
+// Middleware is the Before/After pair registered to load and persist
+// sessions, see New.
+type Middleware struct {
+	cfg Config
+}
+
+// New returns a session Middleware. Register its Before and After methods
+// with Router.UseBefore/Router.UseAfter (or atreugo.Middlewares) respectively.
+//
+// It returns ErrStoreRequired or ErrSecretRequired if Config.Store or
+// Config.Secret are left unset.
+func New(cfg Config) (*Middleware, error) {
+	if cfg.Store == nil {
+		return nil, ErrStoreRequired
+	}
+
+	if len(cfg.Secret) == 0 {
+		return nil, ErrSecretRequired
+	}
+
+	cfg.setDefaults()
+
+	return &Middleware{cfg: cfg}, nil
+}
+
+// Before lazily loads the session on first access via Get(ctx), and doesn't
+// touch the Store for requests that never read or write it.
+func (m *Middleware) Before(ctx *atreugo.RequestCtx) error {
+	ctx.SetUserValue(sessionUserValueKey, m)
+
+	return ctx.Next()
+}
+
+// After persists the session if it was modified during the request and
+// (re)writes the session cookie each time it does so; a destroyed session is
+// deleted from the Store and its cookie cleared instead.
+func (m *Middleware) After(ctx *atreugo.RequestCtx) error {
+	s, ok := loadedSession(ctx)
+	if !ok || !s.isDirty() {
+		return ctx.Next()
+	}
+
+	if s.destroy {
+		_ = m.cfg.Store.Delete(s.id)
+		clearCookie(ctx, &m.cfg)
+
+		return ctx.Next()
+	}
+
+	id := s.id
+	if s.regenID != "" {
+		_ = m.cfg.Store.Delete(s.id)
+		id = s.regenID
+	}
+
+	raw, err := m.cfg.Serializer.Encode(s.values)
+	if err != nil {
+		return err
+	}
+
+	if m.cfg.SecretBox != nil {
+		raw, err = m.cfg.SecretBox.encrypt(raw)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := m.cfg.Store.Save(id, raw, m.cfg.MaxAge); err != nil {
+		return err
+	}
+
+	setCookie(ctx, &m.cfg, id)
+
+	return ctx.Next()
+}
+
+// Get returns the Session for the current request, lazily loading it from
+// the Store on first call. It always returns a usable, possibly empty,
+// Session - callers don't need to check for nil.
+func Get(ctx *atreugo.RequestCtx) *Session {
+	if s, ok := loadedSession(ctx); ok {
+		return s
+	}
+
+	m, ok := ctx.UserValue(sessionUserValueKey).(*Middleware)
+	if !ok {
+		s := newSession(newSessionID(), nil)
+		ctx.SetUserValue(sessionResultUserValueKey, s)
+
+		return s
+	}
+
+	s := m.load(ctx)
+	ctx.SetUserValue(sessionResultUserValueKey, s)
+
+	return s
+}
+
+const sessionResultUserValueKey = "__atreugo_session"
+
+func loadedSession(ctx *atreugo.RequestCtx) (*Session, bool) {
+	s, ok := ctx.UserValue(sessionResultUserValueKey).(*Session)
+
+	return s, ok
+}
+
+func (m *Middleware) load(ctx *atreugo.RequestCtx) *Session {
+	id, ok := verifiedCookie(ctx, &m.cfg)
+	if !ok {
+		return newSession(newSessionID(), nil)
+	}
+
+	raw, err := m.cfg.Store.Get(id)
+	if err != nil {
+		return newSession(newSessionID(), nil)
+	}
+
+	if m.cfg.SecretBox != nil {
+		raw, err = m.cfg.SecretBox.decrypt(raw)
+		if err != nil {
+			return newSession(newSessionID(), nil)
+		}
+	}
+
+	values, err := m.cfg.Serializer.Decode(raw)
+	if err != nil {
+		return newSession(newSessionID(), nil)
+	}
+
+	return newSession(id, values)
+}
+
+func newSessionID() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func sign(secret []byte, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func setCookie(ctx *atreugo.RequestCtx, cfg *Config, id string) {
+	value := id + "." + sign(cfg.Secret, id)
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(cfg.CookieName)
+	cookie.SetValue(value)
+	cookie.SetPath(cfg.Path)
+	cookie.SetDomain(cfg.Domain)
+	cookie.SetHTTPOnly(true)
+	cookie.SetSecure(*cfg.Secure)
+	cookie.SetSameSite(cfg.SameSite)
+	cookie.SetMaxAge(int(cfg.MaxAge.Seconds()))
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+func clearCookie(ctx *atreugo.RequestCtx, cfg *Config) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(cfg.CookieName)
+	cookie.SetValue("")
+	cookie.SetPath(cfg.Path)
+	cookie.SetDomain(cfg.Domain)
+	cookie.SetMaxAge(-1)
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+func verifiedCookie(ctx *atreugo.RequestCtx, cfg *Config) (string, bool) {
+	raw := string(ctx.Request.Header.Cookie(cfg.CookieName))
+	if raw == "" {
+		return "", false
+	}
+
+	idx := strings.LastIndexByte(raw, '.')
+	if idx < 0 {
+		return "", false
+	}
+
+	id, sig := raw[:idx], raw[idx+1:]
+	if !hmac.Equal([]byte(sig), []byte(sign(cfg.Secret, id))) {
+		return "", false
+	}
+
+	return id, true
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewRequiresStore(t *testing.T) {
+	_, err := New(Config{Secret: []byte("secret")})
+	if !errors.Is(err, ErrStoreRequired) {
+		t.Errorf("New() error = %v, want ErrStoreRequired", err)
+	}
+}
+
+func TestNewRequiresSecret(t *testing.T) {
+	_, err := New(Config{Store: NewMemoryStore()})
+	if !errors.Is(err, ErrSecretRequired) {
+		t.Errorf("New() error = %v, want ErrSecretRequired", err)
+	}
+}
+
+func TestNewAppliesDefaults(t *testing.T) {
+	mw, err := New(Config{Store: NewMemoryStore(), Secret: []byte("secret")})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if mw.cfg.CookieName != defaultCookieName {
+		t.Errorf("cfg.CookieName = %q, want %q", mw.cfg.CookieName, defaultCookieName)
+	}
+
+	if mw.cfg.Secure == nil || !*mw.cfg.Secure {
+		t.Error("cfg.Secure should default to true")
+	}
+}
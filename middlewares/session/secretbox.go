@@ -0,0 +1,68 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// ErrDecryptFailed is returned by SecretBox when a payload can't be
+// decrypted with any of the configured keys.
+var ErrDecryptFailed = errors.New("session: failed to decrypt payload with any key")
+
+// SecretBox encrypts session payloads at rest with AES-GCM.
+//
+// Keys[0] is always used to encrypt; every key in Keys is tried in order
+// when decrypting, so a secret can be rotated by prepending the new key
+// and keeping the old one around until existing sessions expire.
+type SecretBox struct {
+	Keys [][]byte
+}
+
+func (b *SecretBox) encrypt(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(b.Keys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (b *SecretBox) decrypt(ciphertext []byte) ([]byte, error) {
+	for _, key := range b.Keys {
+		gcm, err := newGCM(key)
+		if err != nil {
+			continue
+		}
+
+		nonceSize := gcm.NonceSize()
+		if len(ciphertext) < nonceSize {
+			continue
+		}
+
+		nonce, data := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+		plaintext, err := gcm.Open(nil, nonce, data, nil)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, ErrDecryptFailed
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
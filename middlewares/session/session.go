@@ -0,0 +1,113 @@
+package session
+
+import "sync"
+
+// Session is a typed, dirty-tracking view over the data saved for one
+// request's session id.
+type Session struct {
+	mu      sync.RWMutex
+	id      string
+	values  map[string]interface{}
+	flashes []interface{}
+	dirty   bool
+	destroy bool
+	regenID string
+}
+
+func newSession(id string, values map[string]interface{}) *Session {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	return &Session{id: id, values: values}
+}
+
+// ID returns the current session id.
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.id
+}
+
+// Get returns the value saved under key, or nil if it doesn't exist.
+func (s *Session) Get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.values[key]
+}
+
+// Set saves value under key and marks the session dirty so it gets persisted.
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes key from the session.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Flash queues value to be read once by the next request's FlashMessages,
+// then discarded.
+func (s *Session) Flash(value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flashes, _ := s.values["_flashes"].([]interface{})
+	s.values["_flashes"] = append(flashes, value)
+	s.dirty = true
+}
+
+// FlashMessages returns and clears the messages queued by Flash on a
+// previous request.
+func (s *Session) FlashMessages() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flashes, _ := s.values["_flashes"].([]interface{})
+	delete(s.values, "_flashes")
+
+	if len(flashes) > 0 {
+		s.dirty = true
+	}
+
+	return flashes
+}
+
+// Regenerate schedules the session to be saved under a new, freshly
+// generated id, invalidating the old one. Useful after privilege changes
+// such as login, to prevent session fixation.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.regenID = newSessionID()
+	s.dirty = true
+}
+
+// Destroy clears all data and schedules the session to be deleted from the
+// store and its cookie cleared.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]interface{})
+	s.destroy = true
+	s.dirty = true
+}
+
+func (s *Session) isDirty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.dirty
+}
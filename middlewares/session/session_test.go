@@ -0,0 +1,95 @@
+package session
+
+import "testing"
+
+func TestSessionGetSet(t *testing.T) {
+	s := newSession("id1", nil)
+
+	if s.isDirty() {
+		t.Fatal("new session is dirty before any write")
+	}
+
+	s.Set("user", "alice")
+
+	if !s.isDirty() {
+		t.Fatal("session is not dirty after Set")
+	}
+
+	if got := s.Get("user"); got != "alice" {
+		t.Errorf("Get(%q) = %v, want %q", "user", got, "alice")
+	}
+
+	if got := s.Get("missing"); got != nil {
+		t.Errorf("Get(%q) = %v, want nil", "missing", got)
+	}
+}
+
+func TestSessionDelete(t *testing.T) {
+	s := newSession("id1", map[string]interface{}{"user": "alice"})
+
+	s.Delete("user")
+
+	if got := s.Get("user"); got != nil {
+		t.Errorf("Get(%q) after Delete = %v, want nil", "user", got)
+	}
+
+	if !s.isDirty() {
+		t.Fatal("session is not dirty after Delete")
+	}
+}
+
+func TestSessionFlash(t *testing.T) {
+	s := newSession("id1", nil)
+
+	s.Flash("welcome back")
+	s.Flash("you have 3 new messages")
+
+	messages := s.FlashMessages()
+	if len(messages) != 2 {
+		t.Fatalf("len(FlashMessages()) = %d, want 2", len(messages))
+	}
+
+	if messages[0] != "welcome back" || messages[1] != "you have 3 new messages" {
+		t.Errorf("FlashMessages() = %v, want the values in insertion order", messages)
+	}
+
+	if got := s.FlashMessages(); len(got) != 0 {
+		t.Errorf("FlashMessages() called again = %v, want empty - flashes are read once", got)
+	}
+}
+
+func TestSessionRegenerate(t *testing.T) {
+	s := newSession("original-id", nil)
+
+	s.Regenerate()
+
+	if !s.isDirty() {
+		t.Fatal("session is not dirty after Regenerate")
+	}
+
+	if s.ID() != "original-id" {
+		t.Errorf("ID() = %q, want the original id until After persists regenID", s.ID())
+	}
+
+	if s.regenID == "" || s.regenID == "original-id" {
+		t.Errorf("regenID = %q, want a freshly generated, different id", s.regenID)
+	}
+}
+
+func TestSessionDestroy(t *testing.T) {
+	s := newSession("id1", map[string]interface{}{"user": "alice"})
+
+	s.Destroy()
+
+	if !s.destroy {
+		t.Fatal("destroy flag not set after Destroy")
+	}
+
+	if !s.isDirty() {
+		t.Fatal("session is not dirty after Destroy")
+	}
+
+	if got := s.Get("user"); got != nil {
+		t.Errorf("Get(%q) after Destroy = %v, want nil - values should be cleared", "user", got)
+	}
+}
@@ -0,0 +1,26 @@
+// Package session provides a pluggable session middleware for atreugo.
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when no data exists for a given id.
+var ErrNotFound = errors.New("session: not found")
+
+// Store persists session data keyed by an opaque session id.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the raw data saved for id, or ErrNotFound if it doesn't exist
+	// or has expired.
+	Get(id string) ([]byte, error)
+
+	// Save persists data for id, expiring it after ttl. A zero ttl means no
+	// expiration.
+	Save(id string, data []byte, ttl time.Duration) error
+
+	// Delete removes any data saved for id.
+	Delete(id string) error
+}
@@ -0,0 +1,150 @@
+package atreugo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrRouteNotFound is returned by Router.URL when no route was registered
+// under the given name.
+var ErrRouteNotFound = errors.New("atreugo: route not found")
+
+type namedRoute struct {
+	url string
+}
+
+const routerUserValueKey = "__atreugo_router"
+
+func (r *Router) root() *Router {
+	for r.parent != nil {
+		r = r.parent
+	}
+
+	return r
+}
+
+// Name registers the path under the given name, so it can later be
+// reversed into a URL with Router.URL/Router.MustURL or RequestCtx.URLFor.
+//
+// Names must be unique across the whole router tree, it panics otherwise.
+func (p *Path) Name(name string) *Path {
+	root := p.router.root()
+
+	root.routeNamesMu.Lock()
+	defer root.routeNamesMu.Unlock()
+
+	if root.routeNames == nil {
+		root.routeNames = make(map[string]*namedRoute)
+	}
+
+	if _, exists := root.routeNames[name]; exists {
+		panic(fmt.Sprintf("atreugo: route name %q is already registered", name))
+	}
+
+	root.routeNames[name] = &namedRoute{url: p.url}
+
+	return p
+}
+
+// URL builds the URL for the route registered under name, substituting params
+// into its path parameters and appending any leftover params as a query string.
+//
+// It returns ErrRouteNotFound if name was never registered with Path.Name.
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	root := r.root()
+
+	root.routeNamesMu.RLock()
+	route, ok := root.routeNames[name]
+	root.routeNamesMu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrRouteNotFound, name)
+	}
+
+	return buildURL(route.url, params)
+}
+
+// MustURL is like URL, but panics instead of returning an error.
+func (r *Router) MustURL(name string, params map[string]string) string {
+	u, err := r.URL(name, params)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// escapeWildcard escapes each path component of a `{name:*}` param value on
+// its own, so literal slashes in it are preserved instead of becoming %2F.
+func escapeWildcard(value string) string {
+	parts := strings.Split(value, "/")
+
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func buildURL(pattern string, params map[string]string) (string, error) {
+	used := make(map[string]bool, len(params))
+	segments := strings.Split(pattern, "/")
+
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		paramName := segment[1 : len(segment)-1]
+		wildcard := false
+
+		if idx := strings.IndexByte(paramName, ':'); idx != -1 {
+			wildcard = paramName[idx+1:] == "*"
+			paramName = paramName[:idx]
+		}
+
+		value, ok := params[paramName]
+		if !ok {
+			return "", fmt.Errorf("atreugo: missing required param %q for route %q", paramName, pattern)
+		}
+
+		if wildcard {
+			segments[i] = escapeWildcard(value)
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+
+		used[paramName] = true
+	}
+
+	result := strings.Join(segments, "/")
+
+	query := make(url.Values, len(params)-len(used))
+
+	for k, v := range params {
+		if !used[k] {
+			query.Set(k, v)
+		}
+	}
+
+	if len(query) > 0 {
+		result += "?" + query.Encode()
+	}
+
+	return result, nil
+}
+
+// URLFor builds the URL for the route registered under name through the
+// router that handled the current request.
+//
+// It returns ErrRouteNotFound if name was never registered with Path.Name.
+func (ctx *RequestCtx) URLFor(name string, params map[string]string) (string, error) {
+	r, ok := ctx.UserValue(routerUserValueKey).(*Router)
+	if !ok {
+		return "", ErrRouteNotFound
+	}
+
+	return r.URL(name, params)
+}
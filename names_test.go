@@ -0,0 +1,93 @@
+package atreugo
+
+import "testing"
+
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no params",
+			pattern: "/users",
+			params:  nil,
+			want:    "/users",
+		},
+		{
+			name:    "path param",
+			pattern: "/users/{id}",
+			params:  map[string]string{"id": "42"},
+			want:    "/users/42",
+		},
+		{
+			name:    "path param is escaped",
+			pattern: "/users/{name}",
+			params:  map[string]string{"name": "a b/c"},
+			want:    "/users/a%20b%2Fc",
+		},
+		{
+			name:    "wildcard param keeps slashes",
+			pattern: "/files/{path:*}",
+			params:  map[string]string{"path": "a/b c/d"},
+			want:    "/files/a/b%20c/d",
+		},
+		{
+			name:    "leftover params become a sorted query string",
+			pattern: "/users/{id}",
+			params:  map[string]string{"id": "1", "b": "2", "a": "3"},
+			want:    "/users/1?a=3&b=2",
+		},
+		{
+			name:    "missing required param",
+			pattern: "/users/{id}",
+			params:  nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildURL(tt.pattern, tt.params)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("buildURL(%q, %v) = nil error, want one", tt.pattern, tt.params)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("buildURL(%q, %v) returned unexpected error: %v", tt.pattern, tt.params, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("buildURL(%q, %v) = %q, want %q", tt.pattern, tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeWildcard(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "no slashes", value: "a b", want: "a%20b"},
+		{name: "preserves slashes", value: "a/b/c", want: "a/b/c"},
+		{name: "escapes each segment", value: "a b/c d", want: "a%20b/c%20d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeWildcard(tt.value); got != tt.want {
+				t.Errorf("escapeWildcard(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
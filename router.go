@@ -1,7 +1,9 @@
 package atreugo
 
 import (
+	"fmt"
 	"net/http"
+	"runtime/debug"
 	"sort"
 	"strings"
 
@@ -11,6 +13,12 @@ import (
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 )
 
+// panicUserValueKey carries the recovered panic value from the
+// fastrouter.Router.PanicHandler into the recoverView built in
+// rebuildEdgeHandlers, so Recover's func runs through the same
+// middleware chain (and RequestCtx pooling) as regular routes.
+const panicUserValueKey = "__atreugo_panic"
+
 func defaultErrorView(ctx *RequestCtx, err error, statusCode int) {
 	ctx.Error(err.Error(), statusCode)
 }
@@ -48,7 +56,7 @@ func newRouter(cfg Config) *Router {
 	router := fastrouter.New()
 	router.HandleOPTIONS = false
 
-	return &Router{
+	r := &Router{
 		router:        router,
 		handleOPTIONS: true,
 		cfg: &routerConfig{
@@ -57,6 +65,13 @@ func newRouter(cfg Config) *Router {
 			logger:    cfg.Logger,
 		},
 	}
+
+	r.notFoundView = defaultNotFoundView
+	r.methodNotAllowedView = defaultMethodNotAllowedView
+	r.recoverFn = defaultRecover
+	r.rebuildEdgeHandlers()
+
+	return r
 }
 
 func (r *Router) mutable(v bool) {
@@ -120,6 +135,7 @@ func (r *Router) handler(fn View, middle Middlewares) fasthttp.RequestHandler {
 
 	return func(ctx *fasthttp.RequestCtx) {
 		actx := AcquireRequestCtx(ctx)
+		actx.SetUserValue(routerUserValueKey, r)
 
 		for i := 0; i < chainLen; i++ {
 			if err := chain[i](actx); err != nil {
@@ -201,6 +217,7 @@ func (r *Router) ListPaths() map[string][]string {
 // WARNING: The previous middlewares configuration could be overridden.
 func (r *Router) Middlewares(middlewares Middlewares) *Router {
 	r.middlewares = middlewares
+	r.rebuildEdgeHandlers()
 
 	return r
 }
@@ -209,6 +226,7 @@ func (r *Router) Middlewares(middlewares Middlewares) *Router {
 // before the execution of the view or group.
 func (r *Router) UseBefore(fns ...Middleware) *Router {
 	r.middlewares.Before = append(r.middlewares.Before, fns...)
+	r.rebuildEdgeHandlers()
 
 	return r
 }
@@ -217,6 +235,7 @@ func (r *Router) UseBefore(fns ...Middleware) *Router {
 // after the execution of the view or group.
 func (r *Router) UseAfter(fns ...Middleware) *Router {
 	r.middlewares.After = append(r.middlewares.After, fns...)
+	r.rebuildEdgeHandlers()
 
 	return r
 }
@@ -224,6 +243,7 @@ func (r *Router) UseAfter(fns ...Middleware) *Router {
 // SkipMiddlewares registers the middlewares that you want to skip when executing the view or group.
 func (r *Router) SkipMiddlewares(fns ...Middleware) *Router {
 	r.middlewares.Skip = append(r.middlewares.Skip, fns...)
+	r.rebuildEdgeHandlers()
 
 	return r
 }
@@ -289,11 +309,11 @@ func (r *Router) RequestHandlerPath(method, url string, handler fasthttp.Request
 // it has the following drawbacks comparing to using manually written fasthttp/atreugo,
 // request handler:
 //
-//     * A lot of useful functionality provided by fasthttp/atreugo is missing
-//       from net/http handler.
-//     * net/http -> fasthttp/atreugo handler conversion has some overhead,
-//       so the returned handler will be always slower than manually written
-//       fasthttp/atreugo handler.
+//   - A lot of useful functionality provided by fasthttp/atreugo is missing
+//     from net/http handler.
+//   - net/http -> fasthttp/atreugo handler conversion has some overhead,
+//     so the returned handler will be always slower than manually written
+//     fasthttp/atreugo handler.
 //
 // So it is advisable using this function only for quick net/http -> fasthttp
 // switching. Then manually convert net/http handlers to fasthttp handlers.
@@ -350,13 +370,30 @@ func (r *Router) StaticCustom(url string, fs *StaticFS) *Path {
 		}
 	}
 
-	stripSlashes := strings.Count(r.getGroupFullPath(url), "/")
+	mountPath := r.getGroupFullPath(url)
+	stripSlashes := strings.Count(mountPath, "/")
 
 	if ffs.PathRewrite == nil && stripSlashes > 0 {
 		ffs.PathRewrite = fasthttp.NewPathSlashesStripper(stripSlashes)
 	}
 
-	return r.RequestHandlerPath(fasthttp.MethodGet, url+"/{filepath:*}", ffs.NewRequestHandler())
+	handler := ffs.NewRequestHandler()
+
+	if fs.Browse {
+		fsHandler := handler
+		handler = func(fctx *fasthttp.RequestCtx) {
+			actx := AcquireRequestCtx(fctx)
+			reqPath := strings.TrimPrefix(string(fctx.Path()), mountPath)
+
+			if !maybeServeBrowseListing(actx, fs, mountPath, reqPath) {
+				fsHandler(fctx)
+			}
+
+			ReleaseRequestCtx(actx)
+		}
+	}
+
+	return r.RequestHandlerPath(fasthttp.MethodGet, url+"/{filepath:*}", handler)
 }
 
 // ServeFile returns HTTP response containing compressed file contents
@@ -364,8 +401,8 @@ func (r *Router) StaticCustom(url string, fs *StaticFS) *Path {
 //
 // HTTP response may contain uncompressed file contents in the following cases:
 //
-//   * Missing 'Accept-Encoding: gzip' request header.
-//   * No write access to directory containing the file.
+//   - Missing 'Accept-Encoding: gzip' request header.
+//   - No write access to directory containing the file.
 //
 // Directory contents is returned if path points to directory.
 func (r *Router) ServeFile(url, filePath string) *Path {
@@ -401,3 +438,95 @@ func (r *Router) Path(method, url string, viewFn View) *Path {
 
 	return p
 }
+
+// NotFound registers a View run for requests that don't match any route.
+//
+// It goes through the same middleware chain (respecting SkipMiddlewares) and
+// RequestCtx pooling as regular routes, unlike setting router.NotFound
+// directly on the underlying fastrouter.Router.
+func (r *Router) NotFound(view View) {
+	r.notFoundView = view
+	r.rebuildEdgeHandlers()
+}
+
+// MethodNotAllowed registers a View run for requests matching a registered
+// path but not its method.
+//
+// It goes through the same middleware chain (respecting SkipMiddlewares) and
+// RequestCtx pooling as regular routes, unlike setting router.MethodNotAllowed
+// directly on the underlying fastrouter.Router.
+func (r *Router) MethodNotAllowed(view View) {
+	r.methodNotAllowedView = view
+	r.rebuildEdgeHandlers()
+}
+
+// Recover registers a function to run when a view panics, replacing the
+// default behaviour of the underlying fastrouter.Router (which lets the panic
+// reach fasthttp.Server and close the connection).
+//
+// If fn returns a non-nil error, it's passed to cfg.ErrorView with a 500
+// status code and a stack trace is logged.
+func (r *Router) Recover(fn func(ctx *RequestCtx, rcv interface{}) error) {
+	r.recoverFn = fn
+	r.rebuildEdgeHandlers()
+}
+
+// rebuildEdgeHandlers (re)builds the fastrouter-level NotFound/
+// MethodNotAllowed/PanicHandler from the views/func registered through
+// NotFound/MethodNotAllowed/Recover, using the router's current
+// middlewares.
+//
+// It must run after construction (when middlewares is still empty) as well
+// as every time UseBefore/UseAfter/SkipMiddlewares/Middlewares mutate it, so
+// these edge cases always go through the same chain as regular routes.
+func (r *Router) rebuildEdgeHandlers() {
+	// r.handler already mixes r.middlewares into whatever it's given via
+	// buildMiddlewares, so the middle argument here must be path-scoped
+	// (like p.middlewares in handlePath), not r.middlewares itself - passing
+	// r.middlewares again would run every global Before/After twice.
+	if r.notFoundView != nil {
+		r.router.NotFound = r.handler(r.notFoundView, Middlewares{})
+	}
+
+	if r.methodNotAllowedView != nil {
+		r.router.MethodNotAllowed = r.handler(r.methodNotAllowedView, Middlewares{})
+	}
+
+	if r.recoverFn != nil {
+		fn := r.recoverFn
+
+		recoverView := func(actx *RequestCtx) error {
+			rcv := actx.UserValue(panicUserValueKey)
+
+			err := fn(actx, rcv)
+			if err != nil {
+				r.cfg.logger.Printf("panic recovered: %s\n%s", err, debug.Stack())
+			}
+
+			return err
+		}
+
+		handler := r.handler(recoverView, Middlewares{})
+
+		r.router.PanicHandler = func(fctx *fasthttp.RequestCtx, rcv interface{}) {
+			fctx.SetUserValue(panicUserValueKey, rcv)
+			handler(fctx)
+		}
+	}
+}
+
+func defaultNotFoundView(ctx *RequestCtx) error {
+	ctx.Error("Not Found", fasthttp.StatusNotFound)
+
+	return nil
+}
+
+func defaultMethodNotAllowedView(ctx *RequestCtx) error {
+	ctx.Error("Method Not Allowed", fasthttp.StatusMethodNotAllowed)
+
+	return nil
+}
+
+func defaultRecover(ctx *RequestCtx, rcv interface{}) error {
+	return fmt.Errorf("recovered panic: %v", rcv)
+}
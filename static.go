@@ -0,0 +1,64 @@
+package atreugo
+
+import (
+	"html/template"
+	"time"
+)
+
+// PathRewriteFunc is a callback used to rewrite the requested path before
+// looking it up on the file system.
+type PathRewriteFunc func(ctx *RequestCtx) []byte
+
+// StaticFS represents settings for serving static files from the given file system.
+type StaticFS struct {
+	// Root is the directory to serve files from.
+	Root string
+
+	// IndexNames is the list of index file names to try serving when a
+	// directory is requested, e.g. []string{"index.html"}.
+	IndexNames []string
+
+	// GenerateIndexPages, when true, generates an index page for missing
+	// index files instead of returning a 404.
+	//
+	// If Browse is enabled, that index page will use Browse/BrowseTemplate
+	// instead of the default fasthttp listing.
+	GenerateIndexPages bool
+
+	// Compress enables transparent response compression if set to true.
+	Compress bool
+
+	// AcceptByteRange enables byte range requests if set to true.
+	AcceptByteRange bool
+
+	// CacheDuration is the expiration duration for inactive file handlers.
+	CacheDuration time.Duration
+
+	// CompressedFileSuffix is the suffix to add to the name of cached
+	// compressed file.
+	CompressedFileSuffix string
+
+	// PathNotFound is called when a requested file is not found.
+	PathNotFound View
+
+	// PathRewrite rewrites the requested path before looking it up on the
+	// file system.
+	PathRewrite PathRewriteFunc
+
+	// Browse enables a Caddy-style directory listing for directories that
+	// don't resolve to one of IndexNames.
+	//
+	// When disabled (the default) directories without an index fall back to
+	// GenerateIndexPages/fasthttp's own behaviour.
+	Browse bool
+
+	// BrowseTemplate is the template used to render directory listings when
+	// Browse is true. If nil, a built-in default template is used.
+	//
+	// The template is executed with a *Listing as its data.
+	BrowseTemplate *template.Template
+
+	// BrowseIgnore is a list of glob patterns (matched with path.Match
+	// against the entry name) excluded from directory listings.
+	BrowseIgnore []string
+}
@@ -0,0 +1,52 @@
+package atreugo
+
+import (
+	"sync"
+
+	fastrouter "github.com/fasthttp/router"
+)
+
+// logger is the minimal interface required from Config.Logger.
+type logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// routerConfig holds the settings shared by a Router and all the groups
+// created from it with NewGroupPath.
+type routerConfig struct {
+	errorView func(ctx *RequestCtx, err error, statusCode int)
+	debug     bool
+	logger    logger
+}
+
+// Router wraps a fastrouter.Router, adding middlewares, static file serving,
+// path groups and the atreugo RequestCtx pooling.
+type Router struct {
+	router *fastrouter.Router
+
+	parent *Router
+	prefix string
+
+	cfg *routerConfig
+
+	middlewares   Middlewares
+	routerMutable bool
+	handleOPTIONS bool
+	customOPTIONS []string
+
+	// notFoundView, methodNotAllowedView and recoverFn are the user-provided
+	// views/func registered through NotFound/MethodNotAllowed/Recover. They
+	// are kept around (instead of only baking them into r.router's handler
+	// fields once) so they can be rebuilt with the router's current
+	// middlewares whenever those change, see rebuildEdgeHandlers.
+	notFoundView         View
+	methodNotAllowedView View
+	recoverFn            func(ctx *RequestCtx, rcv interface{}) error
+
+	// routeNames and routeNamesMu back Path.Name/Router.URL. They only ever
+	// get populated on the root Router (see Router.root), so the whole tree
+	// shares one registry without needing a package-level map keyed by
+	// *Router - which would otherwise keep every Router alive forever.
+	routeNames   map[string]*namedRoute
+	routeNamesMu sync.RWMutex
+}